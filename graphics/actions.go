@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package graphics
+
+import (
+	"sync"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type InputKind int
+
+const (
+	KeyInput InputKind = iota
+	MouseInput
+	ControllerInput
+)
+
+// Input identifies a physical key or button that an action can be bound to.
+type Input struct {
+	Kind InputKind
+	Code uint32
+}
+
+func Key(scancode sdl.Scancode) Input {
+	return Input{Kind: KeyInput, Code: uint32(scancode)}
+}
+
+func MouseButton(button uint8) Input {
+	return Input{Kind: MouseInput, Code: uint32(button)}
+}
+
+func ControllerButton(button uint8) Input {
+	return Input{Kind: ControllerInput, Code: uint32(button)}
+}
+
+// ActionMap binds named actions to keys and buttons so handlers can check
+// for "jump" rather than a hardcoded scancode. Feed it events from the
+// handler's Events method, query it from OnUpdate, and call Update at the end
+// of each OnUpdate to start tracking the next frame.
+type ActionMap struct {
+	lock     sync.Mutex
+	bindings map[string][]Input
+	down     map[Input]bool
+	pressed  map[Input]bool
+	repeats  bool
+}
+
+func NewActionMap() *ActionMap {
+	return &ActionMap{
+		bindings: make(map[string][]Input),
+		down:     make(map[Input]bool),
+		pressed:  make(map[Input]bool),
+	}
+}
+
+// Bind adds inputs to an action. An action is active while any of its
+// inputs is held.
+func (a *ActionMap) Bind(action string, inputs ...Input) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.bindings[action] = append(a.bindings[action], inputs...)
+}
+
+func (a *ActionMap) Unbind(action string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	delete(a.bindings, action)
+}
+
+// Bindings returns a copy of the current bindings, suitable for saving.
+func (a *ActionMap) Bindings() map[string][]Input {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	bindings := make(map[string][]Input, len(a.bindings))
+	for action, inputs := range a.bindings {
+		bindings[action] = append([]Input(nil), inputs...)
+	}
+	return bindings
+}
+
+// LoadBindings replaces all bindings with those given.
+func (a *ActionMap) LoadBindings(bindings map[string][]Input) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.bindings = make(map[string][]Input, len(bindings))
+	for action, inputs := range bindings {
+		a.bindings[action] = append([]Input(nil), inputs...)
+	}
+}
+
+// SetRepeats controls whether keyboard auto-repeat events count as fresh
+// activations for JustActivated. Repeats are ignored by default so that a
+// held key activates an action only once.
+func (a *ActionMap) SetRepeats(allow bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.repeats = allow
+}
+
+// IsRepeat reports whether the event is a keyboard auto-repeat generated
+// while a key is held, rather than a physical key press.
+func IsRepeat(event sdl.Event) bool {
+	e, ok := event.(*sdl.KeyboardEvent)
+	return ok && e.Repeat != 0
+}
+
+// HandleEvent updates the input state from keyboard, mouse button and
+// controller button events. It returns true if the event was one it tracks.
+func (a *ActionMap) HandleEvent(event sdl.Event) bool {
+	switch e := event.(type) {
+	case *sdl.KeyboardEvent:
+		if e.Repeat != 0 {
+			a.repeat(Key(e.Keysym.Scancode))
+		} else {
+			a.set(Key(e.Keysym.Scancode), e.State == sdl.PRESSED)
+		}
+	case *sdl.MouseButtonEvent:
+		a.set(MouseButton(e.Button), e.State == sdl.PRESSED)
+	case *sdl.ControllerButtonEvent:
+		a.set(ControllerButton(e.Button), e.State == sdl.PRESSED)
+	default:
+		return false
+	}
+	return true
+}
+
+func (a *ActionMap) set(input Input, down bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if down && !a.down[input] {
+		a.pressed[input] = true
+	}
+	a.down[input] = down
+}
+
+func (a *ActionMap) repeat(input Input) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.down[input] = true
+	if a.repeats {
+		a.pressed[input] = true
+	}
+}
+
+// IsActive reports whether any input bound to the action is held.
+func (a *ActionMap) IsActive(action string) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for _, input := range a.bindings[action] {
+		if a.down[input] {
+			return true
+		}
+	}
+	return false
+}
+
+// JustActivated reports whether any input bound to the action was pressed
+// since the last call to Update.
+func (a *ActionMap) JustActivated(action string) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for _, input := range a.bindings[action] {
+		if a.pressed[input] {
+			return true
+		}
+	}
+	return false
+}
+
+// Update ends the current frame, clearing the just activated state.
+func (a *ActionMap) Update() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for input := range a.pressed {
+		delete(a.pressed, input)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package graphics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func keyEvent(scancode sdl.Scancode, state uint8, repeat uint8) *sdl.KeyboardEvent {
+	return &sdl.KeyboardEvent{State: state, Repeat: repeat, Keysym: sdl.Keysym{Scancode: scancode}}
+}
+
+func TestActionMap(t *testing.T) {
+	a := NewActionMap()
+	a.Bind("jump", Key(sdl.SCANCODE_SPACE), MouseButton(sdl.BUTTON_LEFT))
+
+	if !a.HandleEvent(keyEvent(sdl.SCANCODE_SPACE, sdl.PRESSED, 0)) {
+		t.Fatal("expected the keyboard event to be handled")
+	}
+	if !a.IsActive("jump") || !a.JustActivated("jump") {
+		t.Fatal("expected jump to be active and just activated")
+	}
+	a.Update()
+	if !a.IsActive("jump") || a.JustActivated("jump") {
+		t.Fatal("expected jump to stay active but not be just activated")
+	}
+	a.HandleEvent(keyEvent(sdl.SCANCODE_SPACE, sdl.RELEASED, 0))
+	if a.IsActive("jump") {
+		t.Fatal("expected jump to be inactive once released")
+	}
+
+	a.HandleEvent(&sdl.MouseButtonEvent{Button: sdl.BUTTON_LEFT, State: sdl.PRESSED})
+	if !a.IsActive("jump") || !a.JustActivated("jump") {
+		t.Fatal("expected the mouse button to activate jump")
+	}
+	if a.IsActive("fire") {
+		t.Fatal("expected an unbound action to be inactive")
+	}
+	if a.HandleEvent(&sdl.MouseMotionEvent{}) {
+		t.Fatal("expected mouse motion to be ignored")
+	}
+}
+
+func TestActionMapBindings(t *testing.T) {
+	a := NewActionMap()
+	a.Bind("jump", Key(sdl.SCANCODE_SPACE))
+	a.Bind("fire", MouseButton(sdl.BUTTON_LEFT), ControllerButton(sdl.CONTROLLER_BUTTON_A))
+	bindings := a.Bindings()
+
+	b := NewActionMap()
+	b.LoadBindings(bindings)
+	if !reflect.DeepEqual(b.Bindings(), bindings) {
+		t.Fatalf("got bindings %v; want %v", b.Bindings(), bindings)
+	}
+
+	// The saved bindings are a copy
+	bindings["jump"][0] = Key(sdl.SCANCODE_W)
+	if a.Bindings()["jump"][0] != Key(sdl.SCANCODE_SPACE) {
+		t.Fatal("modifying the saved bindings changed the action map")
+	}
+
+	b.Unbind("fire")
+	if _, ok := b.Bindings()["fire"]; ok {
+		t.Fatal("expected fire to be unbound")
+	}
+}
+
+func TestActionMapRepeats(t *testing.T) {
+	for _, repeats := range []bool{false, true} {
+		a := NewActionMap()
+		a.SetRepeats(repeats)
+		a.Bind("move", Key(sdl.SCANCODE_RIGHT))
+
+		activations := 0
+		a.HandleEvent(keyEvent(sdl.SCANCODE_RIGHT, sdl.PRESSED, 0))
+		for i := 0; i < 3; i++ {
+			if a.JustActivated("move") {
+				activations++
+			}
+			a.Update()
+			event := keyEvent(sdl.SCANCODE_RIGHT, sdl.PRESSED, 1)
+			if !IsRepeat(event) {
+				t.Fatal("expected a repeat event")
+			}
+			a.HandleEvent(event)
+		}
+
+		want := 1
+		if repeats {
+			want = 3
+		}
+		if activations != want {
+			t.Errorf("with repeats %v got %d activations; want %d", repeats, activations, want)
+		}
+		if !a.IsActive("move") {
+			t.Errorf("with repeats %v expected move to stay active", repeats)
+		}
+	}
+}
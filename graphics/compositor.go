@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package graphics
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type drawCommand struct {
+	layer int
+	fn    func(renderer *sdl.Renderer)
+}
+
+// Compositor collects draw commands tagged with a layer and replays them in
+// ascending layer order. Commands on the same layer run in submission order.
+type Compositor struct {
+	lock     sync.Mutex
+	commands []drawCommand
+}
+
+func (cp *Compositor) Submit(layer int, fn func(renderer *sdl.Renderer)) {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	cp.commands = append(cp.commands, drawCommand{layer: layer, fn: fn})
+}
+
+// Flush runs all submitted commands in layer order and clears the queue for
+// the next frame.
+func (cp *Compositor) Flush(renderer *sdl.Renderer) {
+	cp.lock.Lock()
+	commands := cp.commands
+	cp.commands = nil
+	cp.lock.Unlock()
+
+	sort.SliceStable(commands, func(i, j int) bool {
+		return commands[i].layer < commands[j].layer
+	})
+	for _, command := range commands {
+		command.fn(renderer)
+	}
+}
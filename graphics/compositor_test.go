@@ -0,0 +1,39 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package graphics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestCompositorFlush(t *testing.T) {
+	var cp Compositor
+	var order []string
+	submit := func(layer int, name string) {
+		cp.Submit(layer, func(renderer *sdl.Renderer) {
+			order = append(order, name)
+		})
+	}
+	submit(2, "hud")
+	submit(0, "background")
+	submit(1, "player")
+	submit(0, "clouds")
+	submit(1, "enemy")
+
+	cp.Flush(nil)
+	want := []string{"background", "clouds", "player", "enemy", "hud"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("flushed in order %v; want %v", order, want)
+	}
+
+	order = nil
+	cp.Flush(nil)
+	if len(order) != 0 {
+		t.Fatalf("second flush ran %v; want the queue cleared", order)
+	}
+}
@@ -6,10 +6,16 @@ package graphics
 
 import (
 	"fmt"
-	"runtime"
+	"image"
+	"image/draw"
+	"math"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/jfigge/guilib/graphics/fonts"
+
 	"github.com/veandco/go-sdl2/sdl"
 )
 
@@ -22,159 +28,668 @@ const (
 	stopped
 )
 
+const (
+	defaultFrameRate = 60
+	spinThreshold    = 2 * time.Millisecond
+)
+
 type Canvas struct {
 	*sdl.Window
-	state          runState
-	renderer       *sdl.Renderer
-	handler        GUIHandler
-	glwg           sync.WaitGroup
-	frameRateTimer *time.Ticker
-	done           chan bool
-	lock           sync.Mutex
+	id        uint32
+	state     atomic.Int32
+	stepped   atomic.Bool
+	renderer  *sdl.Renderer
+	handler   GUIHandler
+	glwg      sync.WaitGroup
+	frameRate int
+	uncapped  bool
+	deltaTime time.Duration
+	timeScale atomic.Uint64
+	workTime  time.Duration
+	flags     uint32
+	opacity   float32
+	postDraw  func(renderer *sdl.Renderer)
+	onPanic   func(recovered any, stack []byte)
+	resized   bool
+	posted    []func(renderer *sdl.Renderer)
+	postLock  sync.Mutex
+	width     int32
+	height    int32
+	outputW   int32
+	outputH   int32
+	logicalW  int32
+	logicalH  int32
+	scaleX    float32
+	scaleY    float32
+	done      chan bool
+	quitOnce  sync.Once
+	lock      sync.Mutex
 }
 
-func Open(title string, width, height int32, handler GUIHandler) {
-	sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "0")
-	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
-		panic(fmt.Errorf("failed to started sdl: %w", err))
+var (
+	sdlOnce    sync.Once
+	sdlErr     error
+	canvasLock sync.Mutex
+	canvases   = make(map[uint32]*Canvas)
+)
+
+// Open creates a single canvas and runs the event loop until it is closed.
+// It is shorthand for New followed by Run.
+func Open(title string, width, height int32, handler GUIHandler, options ...ConfigOption) {
+	New(title, width, height, handler, options...)
+	Run()
+}
+
+// New creates a canvas with its own window and renderer without entering the
+// event loop. Several canvases may be created and then driven together by
+// Run. New must be called from the goroutine that runs the event loop.
+func New(title string, width, height int32, handler GUIHandler, options ...ConfigOption) *Canvas {
+	sdlOnce.Do(func() {
+		sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "0")
+		if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
+			sdlErr = fmt.Errorf("failed to started sdl: %w", err)
+		}
+	})
+	if sdlErr != nil {
+		panic(sdlErr)
 	}
 
-	window, err := sdl.CreateWindow(
+	c := &Canvas{
+		frameRate: defaultFrameRate,
+		opacity:   1,
+		scaleX:    1,
+		scaleY:    1,
+		done:      make(chan bool),
+	}
+	c.SetTimeScale(1)
+	for _, option := range options {
+		option(c)
+	}
+
+	var err error
+	c.Window, err = sdl.CreateWindow(
 		title,
 		sdl.WINDOWPOS_UNDEFINED,
 		sdl.WINDOWPOS_UNDEFINED,
 		width, height,
-		sdl.WINDOW_OPENGL,
+		c.flags,
 	)
 	if err != nil {
 		panic(fmt.Errorf("failed to create window: %w", err))
 	}
-	c := &Canvas{
-		Window: window,
-		state:  initialized,
-		done:   make(chan bool),
+	c.id, err = c.GetID()
+	if err != nil {
+		panic(fmt.Errorf("failed to get window id: %w", err))
+	}
+	if c.opacity < 1 {
+		if err = c.SetWindowOpacity(c.opacity); err != nil {
+			ErrorTrap(fmt.Errorf("failed to set window opacity: %w", err))
+		}
 	}
 
-	c.renderer, err = sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED) //|sdl.RENDERER_PRESENTVSYNC)
+	c.renderer, err = sdl.CreateRenderer(c.Window, -1, sdl.RENDERER_ACCELERATED) //|sdl.RENDERER_PRESENTVSYNC)
 	if err != nil {
 		panic(fmt.Errorf("failed to create renderer: %w", err))
 	}
+	if c.logicalW > 0 && c.logicalH > 0 {
+		if err = c.renderer.SetLogicalSize(c.logicalW, c.logicalH); err != nil {
+			panic(fmt.Errorf("failed to set logical size: %w", err))
+		}
+	}
+	c.updateSize()
 	c.handler = handler
-	c.handler.Init(c)
-	c.start()
+
+	canvasLock.Lock()
+	defer canvasLock.Unlock()
+	canvases[c.id] = c
+	return c
+}
+
+// Run starts every canvas created with New and processes events, routing
+// window specific events to the canvas that owns the window and broadcasting
+// the rest. Canvases created while running are started on the next pass.
+// Canvases driven by Step are left to their host, and receive none of the
+// events Run polls. Run returns once every canvas it started has quit, and
+// shuts down SDL if no canvases remain.
+func Run() {
+	fmt.Println("Event loop starting")
+	for {
+		for _, c := range runCanvases() {
+			switch c.getState() {
+			case initialized:
+				c.start()
+			case terminating:
+				c.stop()
+			}
+		}
+		if len(runCanvases()) == 0 {
+			break
+		}
+
+		// Process window events
+		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+			routeEvent(event)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	if len(openCanvases()) == 0 {
+		fmt.Println("Quitting sdl")
+		sdl.Quit()
+		sdlOnce = sync.Once{}
+	}
+	fmt.Println("Stopped")
+}
+
+func openCanvases() []*Canvas {
+	canvasLock.Lock()
+	defer canvasLock.Unlock()
+	cs := make([]*Canvas, 0, len(canvases))
+	for _, c := range canvases {
+		cs = append(cs, c)
+	}
+	return cs
+}
+
+// runCanvases returns the open canvases that are driven by Run rather than
+// by Step.
+func runCanvases() []*Canvas {
+	cs := openCanvases()
+	n := 0
+	for _, c := range cs {
+		if !c.stepped.Load() {
+			cs[n] = c
+			n++
+		}
+	}
+	return cs[:n]
+}
+
+// routeEvent dispatches a window specific event to the canvas that owns the
+// window and broadcasts the rest to the canvases driven by Run. Events for a
+// window that has already been destroyed, or whose canvas is driven by Step,
+// are dropped; stepped canvases receive events through HandleEvent.
+func routeEvent(event sdl.Event) {
+	c, windowSpecific := eventCanvas(event)
+	if c != nil {
+		if !c.stepped.Load() {
+			c.HandleEvent(event)
+		}
+	} else if !windowSpecific {
+		for _, c := range runCanvases() {
+			c.HandleEvent(event)
+		}
+	}
+}
+
+// eventCanvas returns the canvas owning the window an event is addressed to,
+// and whether the event is window specific at all. The canvas is nil if the
+// window no longer exists. Events with no window id, such as keyboard events
+// raised while no window has focus, are not window specific.
+func eventCanvas(event sdl.Event) (canvas *Canvas, windowSpecific bool) {
+	var id uint32
+	switch e := event.(type) {
+	case *sdl.WindowEvent:
+		id = e.WindowID
+	case *sdl.KeyboardEvent:
+		id = e.WindowID
+	case *sdl.TextEditingEvent:
+		id = e.WindowID
+	case *sdl.TextInputEvent:
+		id = e.WindowID
+	case *sdl.MouseMotionEvent:
+		id = e.WindowID
+	case *sdl.MouseButtonEvent:
+		id = e.WindowID
+	case *sdl.MouseWheelEvent:
+		id = e.WindowID
+	case *sdl.DropEvent:
+		id = e.WindowID
+	case *sdl.UserEvent:
+		id = e.WindowID
+	default:
+		return nil, false
+	}
+	if id == 0 {
+		return nil, false
+	}
+
+	canvasLock.Lock()
+	defer canvasLock.Unlock()
+	return canvases[id], true
 }
 
 func (c *Canvas) start() {
-	if c.state != initialized {
+	if !c.state.CompareAndSwap(int32(initialized), int32(running)) {
 		return
 	}
-	c.state = running
 
+	// A panic in Init quits the canvas before the game loop is started
+	defer c.panicHandler("init")()
 	c.handler.Init(c)
+	c.glwg.Add(1)
 	go c.gameLoop()
+}
 
-	fmt.Println("Event loop starting")
-	for c.state == running {
-		// Process window events
-		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
-			func() {
-				c.lock.Lock()
-				defer c.lock.Unlock()
-				processed := false
-				if c.handler != nil {
-					processed = c.handler.Events(event)
-				}
-				if !processed {
-					switch e := event.(type) {
-					case *sdl.QuitEvent:
-						fmt.Printf("Quit event: %+v\n", e)
-						c.Quit()
-					}
-				}
-			}()
+// HandleEvent passes an event to the canvas's handler, tracking resizes and
+// quit requests. Run calls it for every event it polls; a host driving the
+// canvas with Step should call it with the events it polls itself.
+func (c *Canvas) HandleEvent(event sdl.Event) {
+	if c.getState() != running {
+		return
+	}
+	defer c.panicHandler("event handler")()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := event.(*sdl.WindowEvent); ok && e.Event == sdl.WINDOWEVENT_SIZE_CHANGED {
+		c.updateSize()
+		c.resized = true
+	}
+	processed := false
+	if c.handler != nil {
+		processed = c.handler.Events(event)
+	}
+	if !processed {
+		switch e := event.(type) {
+		case *sdl.QuitEvent:
+			fmt.Printf("Quit event: %+v\n", e)
+			c.requestQuit()
+		case *sdl.WindowEvent:
+			// SDL only raises a QuitEvent once the last window is closed
+			if e.Event == sdl.WINDOWEVENT_CLOSE && len(openCanvases()) > 1 {
+				c.requestQuit()
+			}
 		}
-		time.Sleep(time.Millisecond * 10)
 	}
+}
+
+func (c *Canvas) requestQuit() {
+	if c.handler == nil || c.handler.OnQuit() {
+		c.Quit()
+	} else {
+		fmt.Println("Quit cancelled by handler")
+	}
+}
+
+func (c *Canvas) stop() {
+	if c.getState() == stopped {
+		return
+	}
+	fmt.Println("Waiting for game loop to exit")
+	c.glwg.Wait()
+	fmt.Println("Game loop complete.")
+	c.setState(stopped)
 
 	fmt.Println("Destroying handlers")
 	c.handler.Destroy()
 	fmt.Println("Destroying renderer")
+	fonts.FreeGlyphs(c.renderer)
 	DeferError(c.renderer.Destroy)
 	fmt.Println("Destroying canvas")
 	DeferError(c.Destroy)
-	fmt.Println("Waiting for event loop to quit")
-	fmt.Println("Quitting sdl")
-	sdl.Quit()
-	fmt.Println("Stopped")
 
+	canvasLock.Lock()
+	defer canvasLock.Unlock()
+	delete(canvases, c.id)
 }
 
+// gameLoop runs the update and draw cycle at the configured frame rate
+// until the canvas quits. The caller must add it to glwg before starting it.
 func (c *Canvas) gameLoop() {
-	c.glwg.Add(1)
-	go func() {
-		defer func() {
-			fmt.Println("Game loop Exited")
-			c.glwg.Done()
-		}()
-		defer c.panicHandler("game loop")()
-		fmt.Println("Game loop starting")
-		c.frameRateTimer = time.NewTicker(time.Second / 60)
-		for c.state == running {
+	defer func() {
+		fmt.Println("Game loop Exited")
+		c.glwg.Done()
+	}()
+	defer c.panicHandler("game loop")()
+	fmt.Println("Game loop starting")
+	frequency := sdl.GetPerformanceFrequency()
+	frameTicks := frequency / uint64(c.frameRate)
+	last := sdl.GetPerformanceCounter()
+	next := last + frameTicks
+	for c.getState() == running {
+		if c.uncapped {
 			select {
 			case <-c.done:
 				fmt.Println("game Loop - Done")
 				return
-			case <-c.frameRateTimer.C:
-				func() {
-					c.lock.Lock()
-					defer c.lock.Unlock()
-					// Update state
-					c.handler.OnUpdate()
-
-					// Handle draw canvas
-					c.handler.OnDraw(c.renderer)
-
-					// Render the image
-					c.renderer.Present()
-				}()
+			default:
 			}
+		} else if !c.waitUntil(next, frequency) {
+			fmt.Println("game Loop - Done")
+			return
+		}
+
+		now := sdl.GetPerformanceCounter()
+		c.frame(ticksToDuration(now-last, frequency))
+		last = now
+
+		// Schedule against fixed deadlines so that the time spent in the
+		// frame, and any oversleep, doesn't accumulate as drift. If the
+		// frame overran by more than a whole frame, skip the missed
+		// deadlines rather than rushing to catch up.
+		end := sdl.GetPerformanceCounter()
+		next += frameTicks
+		if end > next+frameTicks {
+			next = end + frameTicks
 		}
+	}
+}
+
+// frame runs a single update, draw and present under the canvas lock, and
+// records how long it took.
+func (c *Canvas) frame(dt time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	start := sdl.GetPerformanceCounter()
+	defer func() {
+		c.workTime = ticksToDuration(sdl.GetPerformanceCounter()-start, sdl.GetPerformanceFrequency())
 	}()
+	c.deltaTime = time.Duration(float64(dt) * math.Float64frombits(c.timeScale.Load()))
+	if c.resized {
+		c.resized = false
+		if rh, ok := c.handler.(ResizeHandler); ok {
+			rh.OnResize(c.outputW, c.outputH)
+		}
+	}
+
+	// Update state
+	c.handler.OnUpdate()
+
+	// Run work posted from other goroutines
+	c.postLock.Lock()
+	posted := c.posted
+	c.posted = nil
+	c.postLock.Unlock()
+	for _, fn := range posted {
+		fn(c.renderer)
+	}
+
+	// Handle draw canvas
+	c.handler.OnDraw(c.renderer)
+	if c.postDraw != nil {
+		c.postDraw(c.renderer)
+	}
+
+	// Render the image
+	c.renderer.Present()
+}
+
+// Step runs one update, draw and present cycle with the given frame time.
+// It lets a host that owns its own main loop drive a canvas created with New
+// instead of calling Run, feeding it events through HandleEvent. The handler
+// is initialized on the first step, after which Run leaves the canvas alone.
+// Once the canvas quits Step does nothing, and the host should Close it.
+func (c *Canvas) Step(dt time.Duration) {
+	defer c.panicHandler("step")()
+	if c.state.CompareAndSwap(int32(initialized), int32(running)) {
+		c.stepped.Store(true)
+		c.handler.Init(c)
+	}
+	if !c.stepped.Load() || c.getState() != running {
+		return
+	}
+	c.frame(dt)
+}
+
+// Close releases a canvas driven by Step. Canvases driven by Run are
+// released automatically once they quit.
+func (c *Canvas) Close() {
+	c.Quit()
+	c.stop()
+}
+
+// waitUntil blocks until the performance counter reaches the deadline. The
+// bulk of the wait is slept away, and the final stretch is spent spinning on
+// the counter for sub-millisecond precision. Returns false if the canvas was
+// asked to stop while waiting.
+func (c *Canvas) waitUntil(deadline, frequency uint64) bool {
+	for now := sdl.GetPerformanceCounter(); now < deadline; now = sdl.GetPerformanceCounter() {
+		remaining := ticksToDuration(deadline-now, frequency)
+		if remaining <= spinThreshold {
+			continue
+		}
+		timer := time.NewTimer(remaining - spinThreshold)
+		select {
+		case <-c.done:
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+	select {
+	case <-c.done:
+		return false
+	default:
+		return true
+	}
+}
+
+func ticksToDuration(ticks, frequency uint64) time.Duration {
+	return time.Duration(float64(ticks) / float64(frequency) * float64(time.Second))
 }
 
+// Quit signals the game and event loops to stop. It never blocks, so it is
+// safe to call from any goroutine, including from within handler callbacks
+// while the canvas lock is held. The event loop waits for the game loop to
+// exit before tearing down the window.
 func (c *Canvas) Quit() {
-	if c.state == running {
+	quitting := false
+	c.quitOnce.Do(func() {
+		quitting = true
 		fmt.Println("terminating")
-		c.state = terminating
-		fmt.Println("stopping framerate timer")
-		c.frameRateTimer.Stop()
-		fmt.Println("Ending framerate timer")
-		c.done <- true
-		fmt.Println("waiting for game loop to exit")
-		c.glwg.Wait()
-		fmt.Println("Game loop complete.")
-		c.state = stopped
-	} else {
+		c.setState(terminating)
+		close(c.done)
+	})
+	if !quitting {
 		fmt.Println("already quitting")
 	}
 }
 
 func (c *Canvas) IsTerminated() bool {
-	return c.state != running
+	return c.getState() != running
+}
+
+func (c *Canvas) getState() runState {
+	return runState(c.state.Load())
+}
+
+func (c *Canvas) setState(state runState) {
+	c.state.Store(int32(state))
+}
+
+// DeltaTime returns the time elapsed between the start of the previous frame
+// and the start of the current one, multiplied by the time scale.
+func (c *Canvas) DeltaTime() time.Duration {
+	return c.deltaTime
+}
+
+// WorkTime returns how long the previous frame's update, draw and present
+// took, which is useful for judging how much headroom remains per frame.
+func (c *Canvas) WorkTime() time.Duration {
+	return c.workTime
+}
+
+// SetTimeScale scales the simulation time reported by DeltaTime without
+// affecting the frame rate. 0 freezes time, 0.5 is half speed and 2 is double
+// speed. Negative values are treated as 0. It is safe to call from any
+// goroutine, and takes effect from the next frame.
+func (c *Canvas) SetTimeScale(scale float64) {
+	if scale < 0 {
+		scale = 0
+	}
+	c.timeScale.Store(math.Float64bits(scale))
+}
+
+// Post queues a function to run once on the render goroutine during the next
+// frame, before the handler's OnDraw. It is safe to call from any goroutine.
+func (c *Canvas) Post(fn func(renderer *sdl.Renderer)) {
+	c.postLock.Lock()
+	defer c.postLock.Unlock()
+	c.posted = append(c.posted, fn)
+}
+
+// SetPostDraw registers a function to run every frame after the handler's
+// OnDraw and before the frame is presented, e.g. for profiling overlays or
+// frame capture. Pass nil to remove it. It should be called from Init or
+// another handler callback, where the canvas lock is already held.
+func (c *Canvas) SetPostDraw(fn func(renderer *sdl.Renderer)) {
+	c.postDraw = fn
 }
 
 func (c *Canvas) Renderer() *sdl.Renderer {
 	return c.renderer
 }
 
+// Size returns the drawable size of the canvas in pixels, or the logical size
+// when one was configured with LogicalSize. The value is cached and refreshed
+// whenever the window is resized.
+func (c *Canvas) Size() (int32, int32) {
+	return c.width, c.height
+}
+
+// Scale returns the ratio of renderer output pixels to window coordinates.
+// This is greater than one on high-DPI displays when HighDPI is enabled.
+func (c *Canvas) Scale() (float32, float32) {
+	return c.scaleX, c.scaleY
+}
+
+// ToPixels converts window coordinates, such as those reported by
+// sdl.GetMouseState, into the drawing coordinates reported by Size. When a
+// logical size is set the result accounts for the scaling and letterboxing.
+// Note that SDL already maps mouse event coordinates into logical space.
+func (c *Canvas) ToPixels(x, y int32) (int32, int32) {
+	px, py := float32(x)*c.scaleX, float32(y)*c.scaleY
+	if c.logicalW > 0 && c.logicalH > 0 {
+		px, py = toLogical(px, py, c.outputW, c.outputH, c.logicalW, c.logicalH)
+	}
+	return int32(px), int32(py)
+}
+
+// toLogical maps output pixels into logical coordinates, using the same
+// aspect-preserving letterbox SDL applies for a logical size.
+func toLogical(px, py float32, outputW, outputH, logicalW, logicalH int32) (float32, float32) {
+	scale := float32(outputW) / float32(logicalW)
+	if scaleH := float32(outputH) / float32(logicalH); scaleH < scale {
+		scale = scaleH
+	}
+	offsetX := (float32(outputW) - float32(logicalW)*scale) / 2
+	offsetY := (float32(outputH) - float32(logicalH)*scale) / 2
+	return (px - offsetX) / scale, (py - offsetY) / scale
+}
+
+func (c *Canvas) updateSize() {
+	outputW, outputH, err := c.renderer.GetOutputSize()
+	if err != nil {
+		ErrorTrap(fmt.Errorf("failed to get renderer output size: %w", err))
+		return
+	}
+	windowW, windowH := c.GetSize()
+	c.outputW, c.outputH = outputW, outputH
+	c.width, c.height = outputW, outputH
+	if c.logicalW > 0 && c.logicalH > 0 {
+		c.width, c.height = c.logicalW, c.logicalH
+	}
+	c.scaleX, c.scaleY = scaleFactor(outputW, outputH, windowW, windowH)
+}
+
+func scaleFactor(outputW, outputH, windowW, windowH int32) (float32, float32) {
+	if windowW <= 0 || windowH <= 0 {
+		return 1, 1
+	}
+	return float32(outputW) / float32(windowW), float32(outputH) / float32(windowH)
+}
+
+// SetFullscreen switches the window between sdl.WINDOW_FULLSCREEN,
+// sdl.WINDOW_FULLSCREEN_DESKTOP and windowed mode (0). Any other mode is
+// rejected. It should be called from Init or another handler callback, where
+// the canvas lock is already held.
+func (c *Canvas) SetFullscreen(mode uint32) error {
+	switch mode {
+	case 0, sdl.WINDOW_FULLSCREEN, sdl.WINDOW_FULLSCREEN_DESKTOP:
+	default:
+		return fmt.Errorf("invalid fullscreen mode: %#x", mode)
+	}
+	if c.GetFlags()&(sdl.WINDOW_FULLSCREEN|sdl.WINDOW_FULLSCREEN_DESKTOP) == mode {
+		return nil
+	}
+	if err := c.Window.SetFullscreen(mode); err != nil {
+		return fmt.Errorf("failed to set fullscreen mode: %w", err)
+	}
+	c.updateSize()
+	c.resized = true
+	return nil
+}
+
+func (c *Canvas) SetTitle(title string) {
+	c.Window.SetTitle(title)
+}
+
+// SetIcon converts the image to an RGBA surface and uses it as the window icon.
+func (c *Canvas) SetIcon(img image.Image) error {
+	bounds := img.Bounds()
+	nrgba := toNRGBA(img)
+
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, int32(bounds.Dx()), int32(bounds.Dy()), 32, uint32(sdl.PIXELFORMAT_RGBA32))
+	if err != nil {
+		return fmt.Errorf("failed to create icon surface: %w", err)
+	}
+	defer surface.Free()
+
+	if err = surface.Lock(); err != nil {
+		return fmt.Errorf("failed to lock icon surface: %w", err)
+	}
+	pixels := surface.Pixels()
+	rowBytes := bounds.Dx() * 4
+	for y := 0; y < bounds.Dy(); y++ {
+		copy(pixels[y*int(surface.Pitch):y*int(surface.Pitch)+rowBytes], nrgba.Pix[y*nrgba.Stride:y*nrgba.Stride+rowBytes])
+	}
+	surface.Unlock()
+
+	c.Window.SetIcon(surface)
+	return nil
+}
+
+// toNRGBA converts an image to non-premultiplied RGBA, as SDL surfaces expect
+// straight alpha.
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return nrgba
+	}
+	bounds := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	return nrgba
+}
+
+// SetRelativeMouse hides the cursor and confines the mouse to the window.
+// While enabled, mouse motion events continue to be delivered with XRel and
+// YRel holding the movement deltas, even once the cursor reaches the edge.
+func (c *Canvas) SetRelativeMouse(enabled bool) {
+	if sdl.SetRelativeMouseMode(enabled) != 0 {
+		ErrorTrap(fmt.Errorf("failed to set relative mouse mode: %w", sdl.GetError()))
+	}
+}
+
+func (c *Canvas) IsRelativeMouse() bool {
+	return sdl.GetRelativeMouseMode()
+}
+
+func (c *Canvas) ShowCursor(show bool) {
+	toggle := sdl.DISABLE
+	if show {
+		toggle = sdl.ENABLE
+	}
+	_, err := sdl.ShowCursor(toggle)
+	ErrorTrap(err)
+}
+
 func (c *Canvas) panicHandler(name string) func() {
 	return func() {
 		if err := recover(); err != nil {
-			fmt.Printf("Panic detected in %s: %v\n", name, err)
-			for i := 3; i < 10; i++ {
-				if _, file, line, ok := runtime.Caller(i); ok {
-					fmt.Printf("%s:%d\n", file, line)
-				}
+			stack := debug.Stack()
+			fmt.Printf("Panic detected in %s: %v\n%s", name, err, stack)
+			if c.onPanic != nil {
+				c.onPanic(err, stack)
 			}
 			c.Quit()
 		}
@@ -0,0 +1,578 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package graphics
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestMain(m *testing.M) {
+	// Run SDL headless, with a software renderer standing in for the GPU
+	os.Setenv("SDL_VIDEODRIVER", "dummy")
+	os.Setenv("SDL_AUDIODRIVER", "dummy")
+	os.Setenv("SDL_RENDER_DRIVER", "software")
+	os.Exit(m.Run())
+}
+
+type testHandler struct {
+	BaseHandler
+	canvas  *Canvas
+	inits   int
+	updates int
+	draws   int
+	events  int
+	init    func(c *Canvas)
+	update  func(c *Canvas)
+	draw    func(c *Canvas, renderer *sdl.Renderer)
+	event   func(c *Canvas, event sdl.Event) bool
+	quit    func(c *Canvas) bool
+}
+
+func (h *testHandler) Init(canvas *Canvas) {
+	h.canvas = canvas
+	h.inits++
+	if h.init != nil {
+		h.init(canvas)
+	}
+}
+
+func (h *testHandler) Events(event sdl.Event) bool {
+	h.events++
+	if h.event != nil {
+		return h.event(h.canvas, event)
+	}
+	return false
+}
+
+func (h *testHandler) OnUpdate() {
+	h.updates++
+	if h.update != nil {
+		h.update(h.canvas)
+	}
+}
+
+func (h *testHandler) OnDraw(renderer *sdl.Renderer) {
+	h.draws++
+	if h.draw != nil {
+		h.draw(h.canvas, renderer)
+	}
+}
+
+func (h *testHandler) OnQuit() bool {
+	if h.quit != nil {
+		return h.quit(h.canvas)
+	}
+	return true
+}
+
+// runCanvas opens a canvas on its own goroutine and fails the test if it has
+// not shut down within the timeout.
+func runCanvas(t *testing.T, handler GUIHandler, timeout time.Duration, options ...ConfigOption) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Open("test", 320, 240, handler, options...)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatalf("canvas did not shut down within %v", timeout)
+	}
+}
+
+// newCanvas creates a canvas for driving with Step, closed when the test ends.
+func newCanvas(t *testing.T, handler GUIHandler, options ...ConfigOption) *Canvas {
+	t.Helper()
+	c := New("test", 320, 240, handler, options...)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestQuitFromOnUpdate(t *testing.T) {
+	h := &testHandler{
+		update: func(c *Canvas) {
+			c.Quit()
+		},
+	}
+	runCanvas(t, h, 5*time.Second)
+	if h.updates == 0 {
+		t.Fatal("expected OnUpdate to run before quitting")
+	}
+}
+
+func TestQuitTwiceFromOnDraw(t *testing.T) {
+	h := &testHandler{
+		draw: func(c *Canvas, renderer *sdl.Renderer) {
+			c.Quit()
+			c.Quit()
+		},
+	}
+	runCanvas(t, h, 5*time.Second)
+}
+
+func TestRouteEvent(t *testing.T) {
+	ha, hb := &testHandler{}, &testHandler{}
+	a, b := newCanvas(t, ha), newCanvas(t, hb)
+	a.setState(running)
+	b.setState(running)
+
+	routeEvent(&sdl.WindowEvent{Type: sdl.WINDOWEVENT, WindowID: a.id, Event: sdl.WINDOWEVENT_EXPOSED})
+	if ha.events != 1 || hb.events != 0 {
+		t.Fatalf("window event for a reached a=%d, b=%d; want 1, 0", ha.events, hb.events)
+	}
+
+	closed := New("closed", 320, 240, &testHandler{})
+	closed.Close()
+	routeEvent(&sdl.KeyboardEvent{Type: sdl.KEYDOWN, WindowID: closed.id})
+	if ha.events != 1 || hb.events != 0 {
+		t.Fatalf("event for a destroyed window reached a=%d, b=%d; want 1, 0", ha.events, hb.events)
+	}
+
+	routeEvent(&sdl.ControllerButtonEvent{Type: sdl.CONTROLLERBUTTONDOWN})
+	if ha.events != 2 || hb.events != 1 {
+		t.Fatalf("controller event reached a=%d, b=%d; want 2, 1", ha.events, hb.events)
+	}
+
+	routeEvent(&sdl.KeyboardEvent{Type: sdl.KEYDOWN})
+	if ha.events != 3 || hb.events != 2 {
+		t.Fatalf("keyboard event without a window reached a=%d, b=%d; want 3, 2", ha.events, hb.events)
+	}
+}
+
+func TestRouteEventSkipsSteppedCanvas(t *testing.T) {
+	h := &testHandler{}
+	c := newCanvas(t, h)
+	c.Step(time.Second / 60)
+
+	routeEvent(&sdl.WindowEvent{Type: sdl.WINDOWEVENT, WindowID: c.id, Event: sdl.WINDOWEVENT_EXPOSED})
+	routeEvent(&sdl.QuitEvent{Type: sdl.QUIT})
+	if h.events != 0 {
+		t.Fatalf("got %d routed events; want none for a canvas driven by Step", h.events)
+	}
+	if c.IsTerminated() {
+		t.Fatal("a routed quit event stopped a canvas driven by Step")
+	}
+}
+
+func TestStep(t *testing.T) {
+	h := &testHandler{}
+	c := newCanvas(t, h)
+	const n = 5
+	for i := 0; i < n; i++ {
+		c.Step(time.Second / 60)
+	}
+	if h.inits != 1 || h.updates != n || h.draws != n {
+		t.Fatalf("got %d inits, %d updates and %d draws; want 1, %d and %d", h.inits, h.updates, h.draws, n, n)
+	}
+}
+
+func TestStepHandleEvent(t *testing.T) {
+	h := &testHandler{}
+	c := newCanvas(t, h)
+	c.Step(time.Second / 60)
+	c.HandleEvent(&sdl.QuitEvent{Type: sdl.QUIT})
+	if h.events != 1 {
+		t.Fatalf("got %d events; want 1", h.events)
+	}
+	if !c.IsTerminated() {
+		t.Fatal("expected the quit event to terminate the canvas")
+	}
+	c.Step(time.Second / 60)
+	if h.updates != 1 {
+		t.Fatalf("got %d updates; want no more after quitting", h.updates)
+	}
+}
+
+func TestRunSkipsSteppedCanvas(t *testing.T) {
+	stepped := &testHandler{}
+	c := newCanvas(t, stepped)
+	c.Step(time.Second / 60)
+
+	runCanvas(t, &testHandler{update: func(c *Canvas) { c.Quit() }}, 5*time.Second)
+	if stepped.inits != 1 {
+		t.Fatalf("got %d inits of the stepped canvas; want 1", stepped.inits)
+	}
+	if c.IsTerminated() {
+		t.Fatal("Run stopped a canvas driven by Step")
+	}
+	c.Step(time.Second / 60)
+	if stepped.updates != 2 {
+		t.Fatalf("got %d updates of the stepped canvas; want 2", stepped.updates)
+	}
+}
+
+func TestOnPanic(t *testing.T) {
+	tests := map[string]*testHandler{
+		"init":   {init: func(c *Canvas) { panic("init") }},
+		"update": {update: func(c *Canvas) { panic("update") }},
+		"event": {
+			init:  func(c *Canvas) { c.HandleEvent(&sdl.UserEvent{Type: sdl.USEREVENT}) },
+			event: func(c *Canvas, event sdl.Event) bool { panic("event") },
+		},
+	}
+	for name, h := range tests {
+		t.Run(name, func(t *testing.T) {
+			var recovered any
+			var stack []byte
+			runCanvas(t, h, 5*time.Second, OnPanic(func(r any, s []byte) {
+				recovered, stack = r, s
+			}))
+			if recovered != name {
+				t.Fatalf("got recovered value %v; want %q", recovered, name)
+			}
+			if len(stack) == 0 {
+				t.Fatal("expected a stack trace")
+			}
+		})
+	}
+}
+
+func TestSetTitle(t *testing.T) {
+	c := newCanvas(t, &testHandler{})
+	c.SetTitle("renamed")
+	if title := c.GetTitle(); title != "renamed" {
+		t.Fatalf("got title %q; want %q", title, "renamed")
+	}
+}
+
+func TestSetIcon(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.NRGBA{R: 0xFF, A: 0x80}), image.Point{}, draw.Src)
+
+	// Semi-transparent pixels must keep their full color, not premultiplied
+	if got := toNRGBA(img).NRGBAAt(8, 8); got != (color.NRGBA{R: 0xFF, A: 0x80}) {
+		t.Fatalf("got pixel %v; want straight alpha", got)
+	}
+	c := newCanvas(t, &testHandler{})
+	if err := c.SetIcon(img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type resizeHandler struct {
+	testHandler
+	sizes [][2]int32
+}
+
+func (h *resizeHandler) OnResize(width, height int32) {
+	h.sizes = append(h.sizes, [2]int32{width, height})
+}
+
+func TestResizeBurst(t *testing.T) {
+	h := &resizeHandler{}
+	c := newCanvas(t, h, LogicalSize(160, 120))
+	c.Step(time.Second / 60)
+
+	for _, size := range [][2]int32{{400, 300}, {500, 350}, {640, 480}} {
+		c.SetSize(size[0], size[1])
+		c.HandleEvent(&sdl.WindowEvent{Type: sdl.WINDOWEVENT, WindowID: c.id, Event: sdl.WINDOWEVENT_SIZE_CHANGED, Data1: size[0], Data2: size[1]})
+	}
+	c.Step(time.Second / 60)
+	c.Step(time.Second / 60)
+
+	if len(h.sizes) != 1 || h.sizes[0] != [2]int32{640, 480} {
+		t.Fatalf("got resizes %v; want one to 640x480", h.sizes)
+	}
+	if width, height := c.Size(); width != 160 || height != 120 {
+		t.Fatalf("got size %dx%d; want the logical size 160x120", width, height)
+	}
+}
+
+func TestSetFullscreen(t *testing.T) {
+	var setFullscreen func(c *Canvas)
+	c := newCanvas(t, &testHandler{update: func(c *Canvas) { setFullscreen(c) }})
+	const mask = sdl.WINDOW_FULLSCREEN | sdl.WINDOW_FULLSCREEN_DESKTOP
+	var err error
+	setFullscreen = func(c *Canvas) { err = c.SetFullscreen(sdl.WINDOW_RESIZABLE) }
+	c.Step(time.Millisecond)
+	if err == nil {
+		t.Fatal("expected an invalid mode to be rejected")
+	}
+	for _, mode := range []uint32{sdl.WINDOW_FULLSCREEN_DESKTOP, sdl.WINDOW_FULLSCREEN, 0} {
+		setFullscreen = func(c *Canvas) { err = c.SetFullscreen(mode) }
+		c.Step(time.Millisecond)
+		if err != nil {
+			t.Skipf("fullscreen mode %#x is unsupported: %v", mode, err)
+		}
+		if flags := c.GetFlags() & mask; flags != mode {
+			t.Fatalf("got fullscreen flags %#x; want %#x", flags, mode)
+		}
+	}
+}
+
+// frameIntervals runs a canvas at 60 frames per second for the given number
+// of frames, spending work in each OnUpdate, and returns the intervals
+// between frames measured with the performance counter.
+func frameIntervals(t *testing.T, frames int, work time.Duration) []time.Duration {
+	t.Helper()
+	frequency := sdl.GetPerformanceFrequency()
+	var counters []uint64
+	h := &testHandler{
+		update: func(c *Canvas) {
+			counters = append(counters, sdl.GetPerformanceCounter())
+			time.Sleep(work)
+			if len(counters) > frames {
+				c.Quit()
+			}
+		},
+	}
+	runCanvas(t, h, 10*time.Second, FrameRate(60))
+
+	intervals := make([]time.Duration, 0, frames)
+	for i := 1; i < len(counters); i++ {
+		intervals = append(intervals, ticksToDuration(counters[i]-counters[i-1], frequency))
+	}
+	return intervals
+}
+
+// checkFrameRate fails the test unless the intervals average to within 10%
+// of 60 frames per second.
+func checkFrameRate(t *testing.T, intervals []time.Duration) {
+	t.Helper()
+	var total time.Duration
+	for _, interval := range intervals {
+		total += interval
+	}
+	target := time.Second / 60
+	average := total / time.Duration(len(intervals))
+	if average < target*9/10 || average > target*11/10 {
+		t.Fatalf("average frame interval %v; want %v within 10%%", average, target)
+	}
+}
+
+func TestFrameRate(t *testing.T) {
+	checkFrameRate(t, frameIntervals(t, 60, 0))
+}
+
+func TestFrameRateWithWork(t *testing.T) {
+	// Half of each frame is spent working, which leaves enough headroom
+	checkFrameRate(t, frameIntervals(t, 60, 8*time.Millisecond))
+}
+
+func TestWorkTime(t *testing.T) {
+	const work = 5 * time.Millisecond
+	c := newCanvas(t, &testHandler{update: func(c *Canvas) { time.Sleep(work) }})
+	c.Step(time.Second / 60)
+	if c.WorkTime() < work {
+		t.Fatalf("got work time %v; want at least %v", c.WorkTime(), work)
+	}
+}
+
+func TestPost(t *testing.T) {
+	c := newCanvas(t, &testHandler{})
+	const posters, posts = 8, 50
+	var runs [posters * posts]atomic.Int32
+	var wg sync.WaitGroup
+	for p := 0; p < posters; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < posts; i++ {
+				run := &runs[p*posts+i]
+				c.Post(func(renderer *sdl.Renderer) {
+					run.Add(1)
+				})
+			}
+		}(p)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for stepping := true; stepping; {
+		select {
+		case <-done:
+			stepping = false
+		default:
+		}
+		c.Step(time.Millisecond)
+	}
+
+	for i := range runs {
+		if n := runs[i].Load(); n != 1 {
+			t.Fatalf("posted function %d ran %d times; want 1", i, n)
+		}
+	}
+}
+
+func TestScaleFactor(t *testing.T) {
+	tests := []struct {
+		outputW, outputH, windowW, windowH int32
+		scaleX, scaleY                     float32
+	}{
+		{640, 480, 640, 480, 1, 1},
+		{1280, 960, 640, 480, 2, 2},
+		{1280, 720, 640, 480, 2, 1.5},
+		{640, 480, 0, 0, 1, 1},
+	}
+	for _, test := range tests {
+		scaleX, scaleY := scaleFactor(test.outputW, test.outputH, test.windowW, test.windowH)
+		if scaleX != test.scaleX || scaleY != test.scaleY {
+			t.Errorf("scaleFactor(%d, %d, %d, %d) = %v, %v; want %v, %v",
+				test.outputW, test.outputH, test.windowW, test.windowH, scaleX, scaleY, test.scaleX, test.scaleY)
+		}
+	}
+}
+
+func TestToLogical(t *testing.T) {
+	// An 800x600 output letterboxes a 400x400 logical size at 1.5x, with
+	// 100 pixel bars either side
+	tests := []struct {
+		px, py float32
+		lx, ly float32
+	}{
+		{100, 0, 0, 0},
+		{400, 300, 200, 200},
+		{700, 600, 400, 400},
+		{0, 0, -200 / 3.0, 0},
+	}
+	for _, test := range tests {
+		lx, ly := toLogical(test.px, test.py, 800, 600, 400, 400)
+		if math.Abs(float64(lx-test.lx)) > 1e-3 || math.Abs(float64(ly-test.ly)) > 1e-3 {
+			t.Errorf("toLogical(%v, %v) = %v, %v; want %v, %v", test.px, test.py, lx, ly, test.lx, test.ly)
+		}
+	}
+}
+
+func TestLogicalSize(t *testing.T) {
+	c := newCanvas(t, &testHandler{}, LogicalSize(160, 120))
+	if w, h := c.Renderer().GetLogicalSize(); w != 160 || h != 120 {
+		t.Fatalf("got renderer logical size %dx%d; want 160x120", w, h)
+	}
+	if w, h := c.Size(); w != 160 || h != 120 {
+		t.Fatalf("got size %dx%d; want 160x120", w, h)
+	}
+	if x, y := c.ToPixels(160, 120); x != 80 || y != 60 {
+		t.Fatalf("got %d, %d for the window center; want 80, 60", x, y)
+	}
+}
+
+func TestOnQuitCancelled(t *testing.T) {
+	quits := 0
+	h := &testHandler{quit: func(c *Canvas) bool {
+		quits++
+		return false
+	}}
+	c := newCanvas(t, h)
+	c.Step(time.Second / 60)
+	c.HandleEvent(&sdl.QuitEvent{Type: sdl.QUIT})
+	if quits != 1 {
+		t.Fatalf("got %d calls to OnQuit; want 1", quits)
+	}
+	if c.IsTerminated() {
+		t.Fatal("expected the canvas to keep running")
+	}
+	c.Step(time.Second / 60)
+	if h.updates != 2 {
+		t.Fatalf("got %d updates; want 2", h.updates)
+	}
+}
+
+func TestInitOnce(t *testing.T) {
+	h := &testHandler{}
+	h.update = func(c *Canvas) {
+		if h.updates >= 5 {
+			c.Quit()
+		}
+	}
+	runCanvas(t, h, 5*time.Second)
+	if h.inits != 1 {
+		t.Fatalf("got %d calls to Init; want 1", h.inits)
+	}
+	if h.canvas == nil {
+		t.Fatal("expected Init to receive the canvas")
+	}
+}
+
+func TestRelativeMouse(t *testing.T) {
+	c := newCanvas(t, &testHandler{})
+	c.SetRelativeMouse(true)
+	defer c.SetRelativeMouse(false)
+	if !c.IsRelativeMouse() {
+		t.Skip("relative mouse mode is unsupported")
+	}
+	if !sdl.GetRelativeMouseMode() {
+		t.Fatal("expected SDL to report relative mouse mode")
+	}
+	c.SetRelativeMouse(false)
+	if c.IsRelativeMouse() || sdl.GetRelativeMouseMode() {
+		t.Fatal("expected relative mouse mode to be disabled")
+	}
+}
+
+func TestTimeScale(t *testing.T) {
+	var elapsed time.Duration
+	c := newCanvas(t, &testHandler{update: func(c *Canvas) {
+		elapsed += c.DeltaTime()
+	}})
+	c.SetTimeScale(0.5)
+	for i := 0; i < 10; i++ {
+		c.Step(100 * time.Millisecond)
+	}
+	if elapsed != 500*time.Millisecond {
+		t.Fatalf("got %v of simulation time; want 500ms", elapsed)
+	}
+
+	c.SetTimeScale(-1)
+	c.Step(100 * time.Millisecond)
+	if c.DeltaTime() != 0 {
+		t.Fatalf("got delta time %v with a negative scale; want 0", c.DeltaTime())
+	}
+}
+
+func TestSize(t *testing.T) {
+	c := newCanvas(t, &testHandler{})
+	if w, h := c.Size(); w != 320 || h != 240 {
+		t.Fatalf("got size %dx%d; want 320x240", w, h)
+	}
+}
+
+func TestPostDraw(t *testing.T) {
+	var calls []string
+	h := &testHandler{
+		init: func(c *Canvas) {
+			c.SetPostDraw(func(renderer *sdl.Renderer) {
+				calls = append(calls, "post draw")
+				renderer.SetDrawColor(0xFF, 0, 0, 0xFF)
+				renderer.FillRect(nil)
+			})
+		},
+		draw: func(c *Canvas, renderer *sdl.Renderer) {
+			calls = append(calls, "draw")
+			renderer.SetDrawColor(0, 0, 0, 0xFF)
+			renderer.Clear()
+		},
+	}
+	c := newCanvas(t, h)
+	c.Step(time.Second / 60)
+	c.Step(time.Second / 60)
+	want := []string{"draw", "post draw", "draw", "post draw"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("got calls %v; want %v", calls, want)
+	}
+
+	// The hook's drawing must have made it into the presented frame
+	surface, err := c.GetSurface()
+	if err != nil {
+		t.Skipf("window surface is unavailable: %v", err)
+	}
+	if r, g, b, _ := surface.At(10, 10).RGBA(); r>>8 != 0xFF || g != 0 || b != 0 {
+		t.Fatalf("got presented color %x, %x, %x; want red", r>>8, g>>8, b>>8)
+	}
+}
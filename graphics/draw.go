@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package graphics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// DrawPolygon fills a convex polygon in the 0xRRGGBBAA color by splitting it
+// into a fan of triangles and submitting them in a single RenderGeometry
+// call. Concave polygons can't be drawn as a fan and are rejected.
+// RenderGeometry requires SDL 2.0.18 or later.
+func DrawPolygon(renderer *sdl.Renderer, pts []sdl.FPoint, color uint32) error {
+	if len(pts) < 3 {
+		return errors.New("polygon requires at least 3 points")
+	}
+	if !isConvex(pts) {
+		return errors.New("polygon is not convex")
+	}
+
+	if err := renderer.RenderGeometry(nil, fanVertices(pts, toColor(color)), nil); err != nil {
+		return fmt.Errorf("failed to render polygon: %w", err)
+	}
+	return nil
+}
+
+// fanVertices splits a convex polygon into triangles that share its first
+// point, three vertices per triangle.
+func fanVertices(pts []sdl.FPoint, c sdl.Color) []sdl.Vertex {
+	vertices := make([]sdl.Vertex, 0, (len(pts)-2)*3)
+	for i := 1; i < len(pts)-1; i++ {
+		vertices = append(vertices,
+			sdl.Vertex{Position: pts[0], Color: c},
+			sdl.Vertex{Position: pts[i], Color: c},
+			sdl.Vertex{Position: pts[i+1], Color: c},
+		)
+	}
+	return vertices
+}
+
+// DrawLines draws a connected series of line segments in the 0xRRGGBBAA
+// color. Thicknesses of 1 or less use SDL's 1px lines; thicker lines are
+// built from a quad per segment, submitted in a single RenderGeometry call.
+// Segments simply overlap at their joins. Thick lines require SDL 2.0.18 or
+// later for RenderGeometry.
+func DrawLines(renderer *sdl.Renderer, pts []sdl.FPoint, thickness float32, color uint32) error {
+	if len(pts) < 2 {
+		return nil
+	}
+	c := toColor(color)
+	if thickness <= 1 {
+		if err := renderer.SetDrawColor(c.R, c.G, c.B, c.A); err != nil {
+			return fmt.Errorf("failed to set renderer draw color: %w", err)
+		}
+		if err := renderer.DrawLinesF(pts); err != nil {
+			return fmt.Errorf("failed to draw lines: %w", err)
+		}
+		return nil
+	}
+
+	vertices := lineVertices(pts, thickness, c)
+	if len(vertices) == 0 {
+		return nil
+	}
+	if err := renderer.RenderGeometry(nil, vertices, nil); err != nil {
+		return fmt.Errorf("failed to render lines: %w", err)
+	}
+	return nil
+}
+
+// lineVertices builds two triangles per segment, forming a quad of the given
+// thickness centered on it. Zero length segments are skipped.
+func lineVertices(pts []sdl.FPoint, thickness float32, c sdl.Color) []sdl.Vertex {
+	half := thickness / 2
+	vertices := make([]sdl.Vertex, 0, (len(pts)-1)*6)
+	for i := 0; i < len(pts)-1; i++ {
+		a, b := pts[i], pts[i+1]
+		dx, dy := b.X-a.X, b.Y-a.Y
+		length := float32(math.Hypot(float64(dx), float64(dy)))
+		if length == 0 {
+			continue
+		}
+		// Offset perpendicular to the segment by half the thickness
+		nx, ny := -dy/length*half, dx/length*half
+		p1 := sdl.FPoint{X: a.X + nx, Y: a.Y + ny}
+		p2 := sdl.FPoint{X: b.X + nx, Y: b.Y + ny}
+		p3 := sdl.FPoint{X: b.X - nx, Y: b.Y - ny}
+		p4 := sdl.FPoint{X: a.X - nx, Y: a.Y - ny}
+		vertices = append(vertices,
+			sdl.Vertex{Position: p1, Color: c},
+			sdl.Vertex{Position: p2, Color: c},
+			sdl.Vertex{Position: p3, Color: c},
+			sdl.Vertex{Position: p1, Color: c},
+			sdl.Vertex{Position: p3, Color: c},
+			sdl.Vertex{Position: p4, Color: c},
+		)
+	}
+	return vertices
+}
+
+// isConvex checks that every turn around the polygon is in the same
+// direction, and that the turns add up to a single revolution so that self
+// intersecting shapes such as a pentagram are rejected. Collinear points are
+// allowed, but not a polygon whose points all lie on one line.
+func isConvex(pts []sdl.FPoint) bool {
+	sign := 0
+	turning := 0.0
+	for i := range pts {
+		a, b, c := pts[i], pts[(i+1)%len(pts)], pts[(i+2)%len(pts)]
+		ux, uy := b.X-a.X, b.Y-a.Y
+		vx, vy := c.X-b.X, c.Y-b.Y
+		cross := ux*vy - uy*vx
+		switch {
+		case cross > 0 && sign < 0, cross < 0 && sign > 0:
+			return false
+		case cross > 0:
+			sign = 1
+		case cross < 0:
+			sign = -1
+		}
+		turning += math.Atan2(float64(cross), float64(ux*vx+uy*vy))
+	}
+	return sign != 0 && math.Abs(math.Abs(turning)-2*math.Pi) < 1e-3
+}
+
+func toColor(color uint32) sdl.Color {
+	return sdl.Color{R: uint8(color >> 24), G: uint8(color >> 16), B: uint8(color >> 8), A: uint8(color)}
+}
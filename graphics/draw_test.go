@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package graphics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var square = []sdl.FPoint{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+
+func TestFanVertices(t *testing.T) {
+	vertices := fanVertices(square, sdl.Color{R: 0xFF, A: 0xFF})
+	if len(vertices) != 6 {
+		t.Fatalf("got %d vertices; want 2 triangles", len(vertices))
+	}
+	want := []sdl.FPoint{square[0], square[1], square[2], square[0], square[2], square[3]}
+	for i, v := range vertices {
+		if v.Position != want[i] {
+			t.Fatalf("vertex %d at %v; want %v", i, v.Position, want[i])
+		}
+	}
+}
+
+func TestIsConvex(t *testing.T) {
+	var pentagon, pentagram []sdl.FPoint
+	for i := 0; i < 5; i++ {
+		angle := 2 * math.Pi * float64(i) / 5
+		pentagon = append(pentagon, sdl.FPoint{X: float32(math.Cos(angle)) * 10, Y: float32(math.Sin(angle)) * 10})
+	}
+	for _, i := range []int{0, 2, 4, 1, 3} {
+		pentagram = append(pentagram, pentagon[i])
+	}
+
+	tests := map[string]struct {
+		pts  []sdl.FPoint
+		want bool
+	}{
+		"square":           {square, true},
+		"reversed square":  {[]sdl.FPoint{square[3], square[2], square[1], square[0]}, true},
+		"pentagon":         {pentagon, true},
+		"collinear points": {[]sdl.FPoint{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}, true},
+		"pentagram":        {pentagram, false},
+		"all collinear":    {[]sdl.FPoint{{X: 0, Y: 0}, {X: 5, Y: 5}, {X: 10, Y: 10}}, false},
+		"concave":          {[]sdl.FPoint{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 5}, {X: 10, Y: 10}, {X: 0, Y: 10}}, false},
+	}
+	for name, test := range tests {
+		if got := isConvex(test.pts); got != test.want {
+			t.Errorf("%s: got %v; want %v", name, got, test.want)
+		}
+	}
+}
+
+func TestLineVertices(t *testing.T) {
+	pts := []sdl.FPoint{{X: 10, Y: 20}, {X: 50, Y: 20}, {X: 50, Y: 20}, {X: 50, Y: 50}}
+	vertices := lineVertices(pts, 6, sdl.Color{A: 0xFF})
+	if len(vertices) != 12 {
+		t.Fatalf("got %d vertices; want 6 for each of the 2 non-empty segments", len(vertices))
+	}
+	want := []sdl.FPoint{{X: 10, Y: 23}, {X: 50, Y: 23}, {X: 50, Y: 17}, {X: 10, Y: 23}, {X: 50, Y: 17}, {X: 10, Y: 17}}
+	for i, p := range want {
+		if vertices[i].Position != p {
+			t.Fatalf("vertex %d at %v; want %v", i, vertices[i].Position, p)
+		}
+	}
+}
+
+func TestDrawLinesThick(t *testing.T) {
+	var v sdl.Version
+	sdl.GetVersion(&v)
+	if sdl.VERSIONNUM(int(v.Major), int(v.Minor), int(v.Patch)) < sdl.VERSIONNUM(2, 0, 18) {
+		t.Skipf("RenderGeometry requires SDL 2.0.18; linked against %d.%d.%d", v.Major, v.Minor, v.Patch)
+	}
+	renderer := newTestRenderer(t)
+	pts := []sdl.FPoint{{X: 10, Y: 20}, {X: 50, Y: 20}}
+	if err := DrawLines(renderer, pts, 6, 0x00FF00FF); err != nil {
+		t.Fatal(err)
+	}
+	// A 1px line along the segment wouldn't reach 2px either side of it
+	for _, y := range []int32{18, 20, 22} {
+		if c := readPixel(t, renderer, 30, y); c != (sdl.Color{G: 0xFF, A: 0xFF}) {
+			t.Fatalf("got %v at 30, %d; want the line color", c, y)
+		}
+	}
+	if c := readPixel(t, renderer, 30, 26); c.A != 0 {
+		t.Fatalf("got %v at 30, 26; want it outside the line", c)
+	}
+}
@@ -7,8 +7,11 @@ package fonts
 import (
 	"fmt"
 	"image/color"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/veandco/go-sdl2/sdl"
 	"github.com/veandco/go-sdl2/ttf"
@@ -21,9 +24,15 @@ const (
 )
 
 var (
+	// lock guards the loaded fonts and glyph caches, which are shared between
+	// the goroutine loading fonts and the game loop rendering with them. Even
+	// measuring text writes to SDL_ttf's internal glyph cache, so all access
+	// to a font is exclusive.
+	lock     sync.Mutex
 	fonts    = []Font{Default}
 	fontSrc  = []string{"Tahoma.ttf"}
 	ttfFonts = make([]*ttf.Font, len(fonts))
+	fontData = make([][]byte, len(fonts))
 )
 
 type Writer struct {
@@ -39,6 +48,34 @@ func (w *Writer) Render(x int32, y int32) error {
 	return w.renderer.Copy(w.texture, w.srcRect, w.destRect)
 }
 
+// RenderEx draws the text at x, y scaled by scale and rotated clockwise by
+// angle degrees about the center of the scaled text. The scale must be
+// positive.
+func (w *Writer) RenderEx(x, y int32, angle float64, scale float32) error {
+	if scale <= 0 {
+		return fmt.Errorf("invalid text scale: %v", scale)
+	}
+	dest := &sdl.Rect{
+		X: x,
+		Y: y,
+		W: int32(float32(w.srcRect.W) * scale),
+		H: int32(float32(w.srcRect.H) * scale),
+	}
+	center := &sdl.Point{X: dest.W / 2, Y: dest.H / 2}
+	return w.renderer.CopyEx(w.texture, w.srcRect, dest, angle, center, sdl.FLIP_NONE)
+}
+
+// Size returns the width and height of the rendered text.
+func (w *Writer) Size() (int32, int32) {
+	return w.srcRect.W, w.srcRect.H
+}
+
+// Texture returns the texture holding the rendered text. The texture remains
+// owned by the Writer and must not be destroyed by the caller; use Close.
+func (w *Writer) Texture() *sdl.Texture {
+	return w.texture
+}
+
 func (w *Writer) Close() {
 	w.texture.Destroy()
 }
@@ -48,44 +85,191 @@ func Fonts() []Font {
 }
 
 func LoadFonts(renderer *sdl.Renderer) {
-	err := ttf.Init()
+	dir, err := os.Getwd()
+	if err != nil {
+		panic(fmt.Errorf("unable to get working directory: %w", err))
+	}
+	err = LoadFontsFS(renderer, os.DirFS(filepath.Join(dir, "resources", "fonts")))
 	if err != nil {
-		panic(fmt.Sprintf("failed to initialize fonts package: %v", err))
+		panic(err)
 	}
+}
 
-	var dir string
-	dir, err = os.Getwd()
+// LoadFontsFS loads the font files from the root of fsys, which allows them
+// to be embedded in the binary with an embed.FS. Fonts loaded by an earlier
+// call are closed as they are replaced.
+func LoadFontsFS(renderer *sdl.Renderer, fsys fs.FS) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	err := ttf.Init()
 	if err != nil {
-		panic(fmt.Errorf("unable to get working directory: %w", err))
+		return fmt.Errorf("failed to initialize fonts package: %w", err)
 	}
-	base := filepath.Join(dir, "resources", "fonts")
+	// Cached glyphs may have been rendered from the fonts being replaced
+	freeGlyphs()
+
 	for i, src := range fontSrc {
-		ttfFonts[i], err = ttf.OpenFont(filepath.Join(base, fontSrc[i]), 15)
+		var data []byte
+		data, err = fs.ReadFile(fsys, src)
 		if err != nil {
-			panic(fmt.Sprintf("failed to load font [%s]: %v", src, err))
+			return fmt.Errorf("failed to read font [%s]: %w", src, err)
 		}
+		var rw *sdl.RWops
+		rw, err = sdl.RWFromMem(data)
+		if err != nil {
+			return fmt.Errorf("failed to wrap font [%s]: %w", src, err)
+		}
+		var font *ttf.Font
+		font, err = ttf.OpenFontRW(rw, 1, 15)
+		if err != nil {
+			return fmt.Errorf("failed to load font [%s]: %w", src, err)
+		}
+		if ttfFonts[i] != nil {
+			ttfFonts[i].Close()
+		}
+		ttfFonts[i] = font
+		// The font reads from the buffer lazily, so keep it referenced
+		fontData[i] = data
 	}
+	return nil
 }
 
 func FreeFonts() {
+	lock.Lock()
+	defer lock.Unlock()
+
+	freeGlyphs()
 	if ttfFonts != nil {
-		for _, ttfFont := range ttfFonts {
+		for i, ttfFont := range ttfFonts {
 			if ttfFont != nil {
 				ttfFont.Close()
+				ttfFonts[i] = nil
 			}
 		}
 	}
+	for i := range fontData {
+		fontData[i] = nil
+	}
 }
 
+// Font returns the underlying SDL_ttf font. It is not guarded by the package
+// lock, so callers must not use it concurrently with other font calls, nor
+// after the fonts are reloaded or freed.
 func (f Font) Font() *ttf.Font {
+	lock.Lock()
+	defer lock.Unlock()
 	return ttfFonts[f]
 }
 
 func (f Font) Size(text string) (int, int, error) {
+	lock.Lock()
+	defer lock.Unlock()
 	return ttfFonts[f].SizeUTF8(text)
 }
 
+// Metrics returns the vertical metrics of the font in pixels. Ascent is the
+// distance from the baseline to the top of the tallest glyph and descent is
+// the (negative) distance to the bottom of the lowest. LineSkip is the
+// recommended spacing between consecutive lines.
+func (f Font) Metrics() (ascent, descent, height, lineSkip int) {
+	lock.Lock()
+	defer lock.Unlock()
+	font := ttfFonts[f]
+	return font.Ascent(), font.Descent(), font.Height(), font.LineSkip()
+}
+
+func (f Font) GlyphMetrics(r rune) (*ttf.GlyphMetrics, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if err := checkRune(r); err != nil {
+		return nil, err
+	}
+	metrics, err := ttfFonts[f].GlyphMetrics(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read glyph metrics: %w", err)
+	}
+	return metrics, nil
+}
+
+// Ellipsize shortens text to fit within maxWidth pixels, replacing the
+// trimmed characters with an ellipsis. Text that already fits is returned
+// unchanged, and an empty string is returned if not even the ellipsis fits.
+func (f Font) Ellipsize(text string, maxWidth int) string {
+	lock.Lock()
+	defer lock.Unlock()
+
+	const ellipsis = "…"
+	if w, _, err := ttfFonts[f].SizeUTF8(text); err == nil && w <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	fits := func(n int) bool {
+		w, _, err := ttfFonts[f].SizeUTF8(string(runes[:n]) + ellipsis)
+		return err == nil && w <= maxWidth
+	}
+
+	// Binary search for the longest prefix that fits alongside the ellipsis,
+	// where -1 means that none does
+	lo, hi := -1, len(runes)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	if lo < 0 {
+		return ""
+	}
+	return string(runes[:lo]) + ellipsis
+}
+
+// MeasureWrapped returns the size of text when word wrapped to wrapWidth
+// pixels. Lines break at whitespace and explicit newlines; a word wider than
+// wrapWidth occupies a line of its own. Runs of spaces and tabs within a line
+// are measured as a single space. A single line is as tall as Size reports,
+// and each further line adds the font's line skip.
+func (f Font) MeasureWrapped(text string, wrapWidth int) (w, h int) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	font := ttfFonts[f]
+	lines := 0
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if cw, _, err := font.SizeUTF8(candidate); err == nil && cw > wrapWidth && line != "" {
+				w = max(w, lineWidth(font, line))
+				lines++
+				line = word
+			} else {
+				line = candidate
+			}
+		}
+		w = max(w, lineWidth(font, line))
+		lines++
+	}
+	return w, font.Height() + (lines-1)*font.LineSkip()
+}
+
+func lineWidth(font *ttf.Font, line string) int {
+	w, _, err := font.SizeUTF8(line)
+	if err != nil {
+		return 0
+	}
+	return w
+}
+
 func (f Font) Writer(renderer *sdl.Renderer, text string, fgColor int32) (*Writer, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
 	surface, err := ttfFonts[f].RenderUTF8Blended(text, sdl.Color(color.RGBA{
 		R: uint8(fgColor >> 24),
 		G: uint8(fgColor >> 16),
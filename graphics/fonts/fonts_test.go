@@ -0,0 +1,281 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package fonts
+
+import (
+	"embed"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+//go:embed testdata
+var testdata embed.FS
+
+// testFontSrc stands in for the application fonts while testing.
+var testFontSrc = []string{"DejaVuSans.ttf"}
+
+// loadTestFonts loads the test fonts from testdata with a software renderer
+// drawing to an in-memory surface.
+func loadTestFonts(t testing.TB) (*sdl.Renderer, fs.FS) {
+	t.Helper()
+	fsys, err := fs.Sub(testdata, "testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := fontSrc
+	fontSrc = testFontSrc
+	t.Cleanup(func() { fontSrc = src })
+
+	renderer := newTestRenderer(t)
+	if err = LoadFontsFS(renderer, fsys); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(FreeFonts)
+	return renderer, fsys
+}
+
+// newTestRenderer returns a software renderer drawing to an in-memory surface.
+func newTestRenderer(t testing.TB) *sdl.Renderer {
+	t.Helper()
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, 320, 240, 32, uint32(sdl.PIXELFORMAT_RGBA32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	renderer, err := sdl.CreateSoftwareRenderer(surface)
+	if err != nil {
+		surface.Free()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		renderer.Destroy()
+		surface.Free()
+	})
+	return renderer
+}
+
+// readPixel returns the color drawn at x, y.
+func readPixel(t testing.TB, renderer *sdl.Renderer, x, y int32) sdl.Color {
+	t.Helper()
+	var c sdl.Color
+	err := renderer.ReadPixels(&sdl.Rect{X: x, Y: y, W: 1, H: 1}, uint32(sdl.PIXELFORMAT_RGBA32), unsafe.Pointer(&c), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestConcurrentLoadAndRender(t *testing.T) {
+	renderer, fsys := loadTestFonts(t)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if err := LoadFontsFS(renderer, fsys); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if _, _, err := Default.Size("Hello, world"); err != nil {
+				t.Error(err)
+				return
+			}
+			w, err := Default.Writer(renderer, "Hello, world", 0x000000FF)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			w.Close()
+		}
+	}()
+	wg.Wait()
+}
+
+func TestMeasureWrapped(t *testing.T) {
+	loadTestFonts(t)
+	_, _, height, lineSkip := Default.Metrics()
+
+	w, h := Default.MeasureWrapped("Hello", 1000)
+	sw, sh, err := Default.Size("Hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w != sw || h != sh || h != height {
+		t.Fatalf("one line measured %dx%d; want %dx%d", w, h, sw, sh)
+	}
+
+	hw, _, _ := Default.Size("Hello,")
+	ww, _, _ := Default.Size("world")
+	w, h = Default.MeasureWrapped("Hello, world", hw+1)
+	if w != max(hw, ww) || h != height+lineSkip {
+		t.Fatalf("two lines measured %dx%d; want %dx%d", w, h, max(hw, ww), height+lineSkip)
+	}
+
+	w2, h2 := Default.MeasureWrapped("Hello,\nworld", 1000)
+	if w2 != w || h2 != h {
+		t.Fatalf("explicit newline measured %dx%d; want %dx%d", w2, h2, w, h)
+	}
+}
+
+func TestEllipsize(t *testing.T) {
+	loadTestFonts(t)
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 50)
+
+	if got := Default.Ellipsize("Hello", 1000); got != "Hello" {
+		t.Fatalf("got %q; want text that fits unchanged", got)
+	}
+
+	got := Default.Ellipsize(text, 200)
+	if !strings.HasSuffix(got, "…") {
+		t.Fatalf("got %q; want an ellipsis", got)
+	}
+	if w, _, _ := Default.Size(got); w > 200 {
+		t.Fatalf("ellipsized text is %d wide; want at most 200", w)
+	}
+	n := len([]rune(got)) - 1
+	if w, _, _ := Default.Size(string([]rune(text)[:n+1]) + "…"); w <= 200 {
+		t.Fatalf("got %q; a longer prefix also fits", got)
+	}
+
+	if got = Default.Ellipsize(text, 1); got != "" {
+		t.Fatalf("got %q; want an empty string when not even the ellipsis fits", got)
+	}
+}
+
+func TestReloadFonts(t *testing.T) {
+	renderer, fsys := loadTestFonts(t)
+	if err := Default.DrawCached(renderer, 0, 0, 0xFFFFFFFF, "Hello"); err != nil {
+		t.Fatal(err)
+	}
+	old := Default.Font()
+	if err := LoadFontsFS(renderer, fsys); err != nil {
+		t.Fatal(err)
+	}
+	if Default.Font() == old {
+		t.Fatal("expected the font to be reopened")
+	}
+	if len(glyphCache) != 0 {
+		t.Fatalf("%d glyphs from the replaced font are still cached", len(glyphCache))
+	}
+
+	if err := renderer.SetDrawColor(0, 0, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := renderer.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	w, err := Default.Writer(renderer, "Hello", 0x7FFFFFFF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err = w.Render(0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	width, height := w.Size()
+	pixels := make([]byte, width*height*4)
+	rect := &sdl.Rect{W: width, H: height}
+	if err = renderer.ReadPixels(rect, uint32(sdl.PIXELFORMAT_RGBA32), unsafe.Pointer(&pixels[0]), int(width)*4); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range pixels {
+		if p != 0 {
+			return
+		}
+	}
+	t.Fatal("expected the text to be drawn")
+}
+
+func TestRenderEx(t *testing.T) {
+	renderer := newTestRenderer(t)
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, 20, 10, 32, uint32(sdl.PIXELFORMAT_RGBA32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer surface.Free()
+	if err = surface.FillRect(nil, 0xFFFFFFFF); err != nil {
+		t.Fatal(err)
+	}
+	texture, err := renderer.CreateTextureFromSurface(surface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &Writer{
+		texture:  texture,
+		renderer: renderer,
+		srcRect:  &sdl.Rect{W: 20, H: 10},
+		destRect: &sdl.Rect{W: 20, H: 10},
+	}
+	defer w.Close()
+
+	for _, scale := range []float32{0, -1} {
+		if err = w.RenderEx(0, 0, 0, scale); err == nil {
+			t.Fatalf("expected scale %v to be rejected", scale)
+		}
+	}
+
+	// Scaled to 40x20 at 100, 100 and turned on end about its center at
+	// 120, 110, the text covers 110-130 across and 90-130 down
+	if err = w.RenderEx(100, 100, 90, 2); err != nil {
+		t.Fatal(err)
+	}
+	if c := readPixel(t, renderer, 120, 95); c.A == 0 {
+		t.Fatal("expected the rotated text above its unrotated position")
+	}
+	if c := readPixel(t, renderer, 105, 110); c.A != 0 {
+		t.Fatal("expected nothing drawn left of the rotated text")
+	}
+}
+
+func TestWriterSize(t *testing.T) {
+	renderer, _ := loadTestFonts(t)
+	const text = "Hello, world"
+	w, err := Default.Writer(renderer, text, 0x7FFFFFFF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	fw, fh, err := Default.Size(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ww, wh := w.Size(); int(ww) != fw || int(wh) != fh {
+		t.Fatalf("writer is %dx%d; want the font size %dx%d", ww, wh, fw, fh)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	loadTestFonts(t)
+	ascent, descent, height, lineSkip := Default.Metrics()
+	if ascent <= 0 || descent > 0 {
+		t.Fatalf("got ascent %d and descent %d; want the descent below the baseline", ascent, descent)
+	}
+	if diff := ascent - descent - height; diff < -1 || diff > 1 {
+		t.Fatalf("ascent %d and descent %d don't add up to height %d", ascent, descent, height)
+	}
+	if lineSkip < height {
+		t.Fatalf("line skip %d is less than the height %d", lineSkip, height)
+	}
+
+	metrics, err := Default.GlyphMetrics('M')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metrics.MaxY > ascent || metrics.Advance <= 0 {
+		t.Fatalf("got glyph metrics %+v; want them within the font ascent", metrics)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package fonts
+
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type glyph struct {
+	texture *sdl.Texture
+	w       int32
+	h       int32
+	advance int32
+}
+
+type glyphKey struct {
+	font     Font
+	renderer *sdl.Renderer
+	ch       rune
+}
+
+type pairKey struct {
+	font   Font
+	first  rune
+	second rune
+}
+
+var (
+	glyphCache = make(map[glyphKey]*glyph)
+	kernCache  = make(map[pairKey]int32)
+)
+
+// DrawCached renders text at x, y by blitting individually cached glyph
+// textures rather than rendering the whole string to a fresh texture. Glyphs
+// are rendered once in white and tinted to the requested 0xRRGGBBAA color at
+// draw time, so repeated or frequently changing text is cheap to draw.
+// Kerning is approximated from the font's advance metrics. Glyphs cached for
+// a renderer must be released with FreeGlyphs before it is destroyed.
+func (f Font) DrawCached(renderer *sdl.Renderer, x, y int32, color uint32, text string) error {
+	// Drawing may populate the caches, so this needs the write lock
+	lock.Lock()
+	defer lock.Unlock()
+
+	r, g, b, a := uint8(color>>24), uint8(color>>16), uint8(color>>8), uint8(color)
+	var prev rune
+	for i, ch := range text {
+		gl, err := f.glyph(renderer, ch)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			x += f.kerning(prev, ch)
+		}
+		if gl.texture != nil {
+			if err = gl.texture.SetColorMod(r, g, b); err != nil {
+				return fmt.Errorf("failed to set glyph color: %w", err)
+			}
+			if err = gl.texture.SetAlphaMod(a); err != nil {
+				return fmt.Errorf("failed to set glyph alpha: %w", err)
+			}
+			err = renderer.Copy(gl.texture, nil, &sdl.Rect{X: x, Y: y, W: gl.w, H: gl.h})
+			if err != nil {
+				return fmt.Errorf("failed to render glyph: %w", err)
+			}
+		}
+		x += gl.advance
+		prev = ch
+	}
+	return nil
+}
+
+func (f Font) glyph(renderer *sdl.Renderer, ch rune) (*glyph, error) {
+	key := glyphKey{font: f, renderer: renderer, ch: ch}
+	if gl, ok := glyphCache[key]; ok {
+		return gl, nil
+	}
+	if err := checkRune(ch); err != nil {
+		return nil, err
+	}
+
+	metrics, err := ttfFonts[f].GlyphMetrics(ch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read glyph metrics: %w", err)
+	}
+	gl := &glyph{advance: int32(metrics.Advance)}
+
+	// Whitespace and other empty glyphs only advance the cursor
+	if metrics.MaxX > metrics.MinX && metrics.MaxY > metrics.MinY {
+		surface, err := ttfFonts[f].RenderGlyphBlended(ch, sdl.Color{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render glyph: %w", err)
+		}
+		defer surface.Free()
+
+		gl.texture, err = renderer.CreateTextureFromSurface(surface)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create texture from surface: %w", err)
+		}
+		gl.w, gl.h = surface.W, surface.H
+	}
+
+	glyphCache[key] = gl
+	return gl, nil
+}
+
+// kerning approximates the spacing adjustment between two glyphs by
+// comparing the measured width of the pair with the sum of their advances.
+func (f Font) kerning(first, second rune) int32 {
+	key := pairKey{font: f, first: first, second: second}
+	if kern, ok := kernCache[key]; ok {
+		return kern
+	}
+
+	var kern int32
+	w, _, err := ttfFonts[f].SizeUTF8(string([]rune{first, second}))
+	m1, err1 := ttfFonts[f].GlyphMetrics(first)
+	m2, err2 := ttfFonts[f].GlyphMetrics(second)
+	if err == nil && err1 == nil && err2 == nil {
+		kern = int32(w - m1.Advance - m2.Advance)
+	}
+	kernCache[key] = kern
+	return kern
+}
+
+// checkRune rejects runes outside the Basic Multilingual Plane, which SDL_ttf's
+// glyph functions truncate to 16 bits and so would draw as the wrong glyph.
+func checkRune(ch rune) error {
+	if ch > 0xFFFF {
+		return fmt.Errorf("glyph %U is not supported", ch)
+	}
+	return nil
+}
+
+// FreeGlyphs releases the glyph textures cached for the renderer. Destroying a
+// renderer frees its textures, so this must be called beforehand.
+func FreeGlyphs(renderer *sdl.Renderer) {
+	lock.Lock()
+	defer lock.Unlock()
+	for key, gl := range glyphCache {
+		if key.renderer != renderer {
+			continue
+		}
+		if gl.texture != nil {
+			gl.texture.Destroy()
+		}
+		delete(glyphCache, key)
+	}
+}
+
+func freeGlyphs() {
+	for key, gl := range glyphCache {
+		if gl.texture != nil {
+			gl.texture.Destroy()
+		}
+		delete(glyphCache, key)
+	}
+	for key := range kernCache {
+		delete(kernCache, key)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package fonts
+
+import (
+	"testing"
+)
+
+func TestDrawCached(t *testing.T) {
+	renderer, _ := loadTestFonts(t)
+	if err := Default.DrawCached(renderer, 10, 10, 0x000000FF, "Hello, world"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Default.DrawCached(renderer, 10, 10, 0x000000FF, "\U0001F600"); err == nil {
+		t.Fatal("expected an error for a rune outside the basic multilingual plane")
+	}
+
+	FreeGlyphs(renderer)
+	for key := range glyphCache {
+		if key.renderer == renderer {
+			t.Fatalf("glyph %q still cached after FreeGlyphs", key.ch)
+		}
+	}
+}
+
+const benchmarkText = "Score: 123456"
+
+func BenchmarkDrawCached(b *testing.B) {
+	renderer, _ := loadTestFonts(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Default.DrawCached(renderer, 10, 10, 0x000000FF, benchmarkText); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	renderer, _ := loadTestFonts(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w, err := Default.Writer(renderer, benchmarkText, 0x000000FF)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err = w.Render(10, 10); err != nil {
+			b.Fatal(err)
+		}
+		w.Close()
+	}
+}
@@ -43,14 +43,45 @@ func (cm *CoreMethods) WriteFrameRate(renderer *sdl.Renderer, x int32, y int32)
 	return nil
 }
 
+// Clear fills the whole render target with a 0xRRGGBBAA color, matching
+// FillRect and DrawRect.
 func (cm *CoreMethods) Clear(renderer *sdl.Renderer, bgColor uint32) error {
-	err := renderer.SetDrawColor(uint8(bgColor>>16), uint8(bgColor>>8), uint8(bgColor), 0xFF)
+	c := toColor(bgColor)
+	err := renderer.SetDrawColor(c.R, c.G, c.B, c.A)
 	if err != nil {
 		return fmt.Errorf("failed to set renderer draw color: %w", err)
 	}
 	err = renderer.Clear()
+	if err != nil {
+		return fmt.Errorf("failed to clear renderer: %w", err)
+	}
+	return nil
+}
+
+// FillRect fills the rectangle with a 0xRRGGBBAA color.
+func (cm *CoreMethods) FillRect(renderer *sdl.Renderer, r sdl.Rect, color uint32) error {
+	c := toColor(color)
+	err := renderer.SetDrawColor(c.R, c.G, c.B, c.A)
 	if err != nil {
 		return fmt.Errorf("failed to set renderer draw color: %w", err)
 	}
+	err = renderer.FillRect(&r)
+	if err != nil {
+		return fmt.Errorf("failed to fill rect: %w", err)
+	}
+	return nil
+}
+
+// DrawRect outlines the rectangle with a 0xRRGGBBAA color.
+func (cm *CoreMethods) DrawRect(renderer *sdl.Renderer, r sdl.Rect, color uint32) error {
+	c := toColor(color)
+	err := renderer.SetDrawColor(c.R, c.G, c.B, c.A)
+	if err != nil {
+		return fmt.Errorf("failed to set renderer draw color: %w", err)
+	}
+	err = renderer.DrawRect(&r)
+	if err != nil {
+		return fmt.Errorf("failed to draw rect: %w", err)
+	}
 	return nil
 }
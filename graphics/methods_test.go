@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package graphics
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// newTestRenderer returns a software renderer drawing to an in-memory surface,
+// cleared to transparent black.
+func newTestRenderer(t *testing.T) *sdl.Renderer {
+	t.Helper()
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, 64, 64, 32, uint32(sdl.PIXELFORMAT_RGBA32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	renderer, err := sdl.CreateSoftwareRenderer(surface)
+	if err != nil {
+		surface.Free()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		renderer.Destroy()
+		surface.Free()
+	})
+	return renderer
+}
+
+// readPixel returns the color drawn at x, y.
+func readPixel(t *testing.T, renderer *sdl.Renderer, x, y int32) sdl.Color {
+	t.Helper()
+	var c sdl.Color
+	err := renderer.ReadPixels(&sdl.Rect{X: x, Y: y, W: 1, H: 1}, uint32(sdl.PIXELFORMAT_RGBA32), unsafe.Pointer(&c), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClear(t *testing.T) {
+	renderer := newTestRenderer(t)
+	var cm CoreMethods
+	if err := cm.Clear(renderer, 0x336699FF); err != nil {
+		t.Fatal(err)
+	}
+	want := sdl.Color{R: 0x33, G: 0x66, B: 0x99, A: 0xFF}
+	if c := readPixel(t, renderer, 0, 0); c != want {
+		t.Fatalf("got %v; want %v", c, want)
+	}
+}
+
+func TestFillRect(t *testing.T) {
+	renderer := newTestRenderer(t)
+	var cm CoreMethods
+	if err := cm.FillRect(renderer, sdl.Rect{X: 10, Y: 10, W: 20, H: 20}, 0x336699FF); err != nil {
+		t.Fatal(err)
+	}
+	if r, g, b, a, _ := renderer.GetDrawColor(); r != 0x33 || g != 0x66 || b != 0x99 || a != 0xFF {
+		t.Fatalf("got draw color %x, %x, %x, %x; want 33, 66, 99, ff", r, g, b, a)
+	}
+
+	want := sdl.Color{R: 0x33, G: 0x66, B: 0x99, A: 0xFF}
+	for _, p := range []sdl.Point{{X: 10, Y: 10}, {X: 20, Y: 20}, {X: 29, Y: 29}} {
+		if c := readPixel(t, renderer, p.X, p.Y); c != want {
+			t.Fatalf("got %v at %v; want %v", c, p, want)
+		}
+	}
+	for _, p := range []sdl.Point{{X: 9, Y: 10}, {X: 30, Y: 29}} {
+		if c := readPixel(t, renderer, p.X, p.Y); c.A != 0 {
+			t.Fatalf("got %v at %v; want it outside the rect", c, p)
+		}
+	}
+}
+
+func TestDrawRect(t *testing.T) {
+	renderer := newTestRenderer(t)
+	var cm CoreMethods
+	if err := cm.DrawRect(renderer, sdl.Rect{X: 10, Y: 10, W: 20, H: 20}, 0xFF0000FF); err != nil {
+		t.Fatal(err)
+	}
+
+	want := sdl.Color{R: 0xFF, A: 0xFF}
+	for _, p := range []sdl.Point{{X: 10, Y: 10}, {X: 29, Y: 10}, {X: 10, Y: 29}, {X: 29, Y: 29}, {X: 20, Y: 10}} {
+		if c := readPixel(t, renderer, p.X, p.Y); c != want {
+			t.Fatalf("got %v at %v; want %v", c, p, want)
+		}
+	}
+	if c := readPixel(t, renderer, 20, 20); c.A != 0 {
+		t.Fatalf("got %v inside the outline; want it left empty", c)
+	}
+}
@@ -15,9 +15,19 @@ type GUIHandler interface {
 	Events(event sdl.Event) bool
 	OnUpdate()
 	OnDraw(renderer *sdl.Renderer)
+	OnQuit() bool
 	Destroy()
 }
 
+// ResizeHandler may optionally be implemented by a GUIHandler to be told
+// when the canvas size changes. Bursts of resize events, such as while the
+// window is dragged, are coalesced into at most one call per frame carrying
+// the final size. The size is the renderer's output size in pixels, even when
+// a fixed LogicalSize is set.
+type ResizeHandler interface {
+	OnResize(width, height int32)
+}
+
 type BaseHandler struct {
 	lock       sync.Mutex
 	destroyers []func()
@@ -53,6 +63,11 @@ func (b *BaseHandler) OnUpdate() {
 func (b *BaseHandler) OnDraw(renderer *sdl.Renderer) {
 }
 
+// OnQuit is called when a quit is requested. Returning false cancels it.
+func (b *BaseHandler) OnQuit() bool {
+	return true
+}
+
 func (b *BaseHandler) Destroy() {
 	b.lock.Lock()
 	defer b.lock.Unlock()
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package graphics
+
+import "github.com/veandco/go-sdl2/sdl"
+
+type ConfigOption func(c *Canvas)
+
+// FrameRate sets the target number of frames per second for the game loop.
+func FrameRate(fps int) ConfigOption {
+	return func(c *Canvas) {
+		if fps > 0 {
+			c.frameRate = fps
+			c.uncapped = false
+		}
+	}
+}
+
+// Uncapped renders frames as fast as possible. The time between frames is
+// still measured and reported through Canvas.DeltaTime.
+func Uncapped() ConfigOption {
+	return func(c *Canvas) {
+		c.uncapped = true
+	}
+}
+
+// HighDPI creates the window with high-DPI support, so the renderer output
+// may be larger than the window size. See Canvas.Scale.
+func HighDPI() ConfigOption {
+	return func(c *Canvas) {
+		c.flags |= sdl.WINDOW_ALLOW_HIGHDPI
+	}
+}
+
+// LogicalSize renders at a fixed logical resolution which SDL scales to fit
+// the window, adding letterbox bars to preserve the aspect ratio.
+func LogicalSize(w, h int32) ConfigOption {
+	return func(c *Canvas) {
+		c.logicalW = w
+		c.logicalH = h
+	}
+}
+
+// WindowOpacity sets the initial window opacity, from 0 (transparent) to 1
+// (opaque). Values outside that range are clamped. Not all platforms support
+// window opacity.
+func WindowOpacity(opacity float32) ConfigOption {
+	return func(c *Canvas) {
+		c.opacity = min(max(opacity, 0), 1)
+	}
+}
+
+func AlwaysOnTop(onTop bool) ConfigOption {
+	return func(c *Canvas) {
+		if onTop {
+			c.flags |= sdl.WINDOW_ALWAYS_ON_TOP
+		} else {
+			c.flags &^= sdl.WINDOW_ALWAYS_ON_TOP
+		}
+	}
+}
+
+// OnPanic registers a callback that receives the recovered value and a full
+// stack trace when a handler callback panics, before the canvas quits. Use it
+// to report crashes to a log file or telemetry.
+func OnPanic(fn func(recovered any, stack []byte)) ConfigOption {
+	return func(c *Canvas) {
+		c.onPanic = fn
+	}
+}
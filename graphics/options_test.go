@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package graphics
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestWindowOpacity(t *testing.T) {
+	for opacity, want := range map[float32]float32{-1: 0, 0.5: 0.5, 2: 1} {
+		var c Canvas
+		WindowOpacity(opacity)(&c)
+		if c.opacity != want {
+			t.Errorf("WindowOpacity(%v) set %v; want %v", opacity, c.opacity, want)
+		}
+	}
+
+	c := newCanvas(t, &testHandler{}, WindowOpacity(0.5))
+	opacity, err := c.GetWindowOpacity()
+	if err != nil || opacity == 1 {
+		t.Skip("window opacity is unsupported")
+	}
+	if opacity < 0.49 || opacity > 0.51 {
+		t.Fatalf("got opacity %v; want 0.5", opacity)
+	}
+}
+
+func TestAlwaysOnTop(t *testing.T) {
+	c := newCanvas(t, &testHandler{}, AlwaysOnTop(true))
+	if c.GetFlags()&sdl.WINDOW_ALWAYS_ON_TOP == 0 {
+		t.Fatal("expected the window to be always on top")
+	}
+	c = newCanvas(t, &testHandler{}, AlwaysOnTop(true), AlwaysOnTop(false))
+	if c.GetFlags()&sdl.WINDOW_ALWAYS_ON_TOP != 0 {
+		t.Fatal("expected the window not to be always on top")
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package tween
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Easing maps linear progress in the range [0, 1] to eased progress.
+type Easing func(t float64) float64
+
+func Linear(t float64) float64 {
+	return t
+}
+
+func QuadIn(t float64) float64 {
+	return t * t
+}
+
+func QuadOut(t float64) float64 {
+	return t * (2 - t)
+}
+
+func QuadInOut(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+func CubicIn(t float64) float64 {
+	return t * t * t
+}
+
+func CubicOut(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+func CubicInOut(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
+
+// ElasticOut springs past the target before settling, so unlike the other
+// easings its values briefly fall outside the From/To range.
+func ElasticOut(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	return math.Pow(2, -10*t)*math.Sin((t-0.075)*(2*math.Pi)/0.3) + 1
+}
+
+// Tween interpolates a value from From to To over Duration.
+type Tween struct {
+	From     float64
+	To       float64
+	Duration time.Duration
+	Easing   Easing
+	elapsed  time.Duration
+}
+
+func New(from, to float64, duration time.Duration, easing Easing) *Tween {
+	if easing == nil {
+		easing = Linear
+	}
+	return &Tween{
+		From:     from,
+		To:       to,
+		Duration: duration,
+		Easing:   easing,
+	}
+}
+
+// Update advances the tween by dt and returns the current value, and whether
+// the tween has reached its end. The final value is always exactly To. A nil
+// Easing is treated as Linear.
+func (t *Tween) Update(dt time.Duration) (float64, bool) {
+	t.elapsed += dt
+	if t.elapsed >= t.Duration {
+		t.elapsed = t.Duration
+		return t.To, true
+	}
+	easing := t.Easing
+	if easing == nil {
+		easing = Linear
+	}
+	progress := float64(t.elapsed) / float64(t.Duration)
+	return t.From + (t.To-t.From)*easing(progress), false
+}
+
+func (t *Tween) Reset() {
+	t.elapsed = 0
+}
+
+type managed struct {
+	tween *Tween
+	apply func(value float64)
+}
+
+// Manager updates a group of tweens together, typically from OnUpdate, and
+// drops them once they complete.
+type Manager struct {
+	lock   sync.Mutex
+	tweens []*managed
+}
+
+// Add registers a tween. Apply is called with the new value on every update,
+// including the final one. It is called without the manager's lock held, so it
+// may Add a follow-on tween when its own completes.
+func (m *Manager) Add(tween *Tween, apply func(value float64)) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.tweens = append(m.tweens, &managed{tween: tween, apply: apply})
+}
+
+func (m *Manager) Update(dt time.Duration) {
+	type update struct {
+		apply func(value float64)
+		value float64
+	}
+
+	m.lock.Lock()
+	updates := make([]update, 0, len(m.tweens))
+	active := m.tweens[:0]
+	for _, mt := range m.tweens {
+		value, done := mt.tween.Update(dt)
+		updates = append(updates, update{apply: mt.apply, value: value})
+		if !done {
+			active = append(active, mt)
+		}
+	}
+	for i := len(active); i < len(m.tweens); i++ {
+		m.tweens[i] = nil
+	}
+	m.tweens = active
+	m.lock.Unlock()
+
+	for _, u := range updates {
+		u.apply(u.value)
+	}
+}
+
+func (m *Manager) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.tweens)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) 2023 by Jason Figge
+ */
+
+package tween
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLinearReachesTo(t *testing.T) {
+	tw := New(10, 20, time.Second, Linear)
+	for i := 0; i < 9; i++ {
+		if _, done := tw.Update(100 * time.Millisecond); done {
+			t.Fatalf("tween finished early after %d steps", i+1)
+		}
+	}
+	value, done := tw.Update(100 * time.Millisecond)
+	if !done || value != 20 {
+		t.Fatalf("got %v, %v at the end; want 20, true", value, done)
+	}
+}
+
+func TestEasingsStayInRange(t *testing.T) {
+	easings := map[string]Easing{
+		"QuadIn":     QuadIn,
+		"QuadOut":    QuadOut,
+		"QuadInOut":  QuadInOut,
+		"CubicIn":    CubicIn,
+		"CubicOut":   CubicOut,
+		"CubicInOut": CubicInOut,
+	}
+	for name, easing := range easings {
+		tw := New(-5, 5, time.Second, easing)
+		for {
+			value, done := tw.Update(10 * time.Millisecond)
+			if value < -5 || value > 5 {
+				t.Fatalf("%s: value %v outside [-5, 5]", name, value)
+			}
+			if done {
+				break
+			}
+		}
+	}
+}
+
+func TestNilEasing(t *testing.T) {
+	tw := &Tween{From: 0, To: 10, Duration: time.Second}
+	if value, _ := tw.Update(500 * time.Millisecond); value != 5 {
+		t.Fatalf("got %v halfway; want 5", value)
+	}
+}
+
+func TestManagerChaining(t *testing.T) {
+	var m Manager
+	var values []float64
+	m.Add(New(0, 1, time.Second, Linear), func(value float64) {
+		values = append(values, value)
+		if value == 1 {
+			m.Add(New(1, 2, time.Second, Linear), func(value float64) {
+				values = append(values, value)
+			})
+		}
+	})
+
+	m.Update(time.Second)
+	if m.Len() != 1 {
+		t.Fatalf("got %d tweens after the first completed; want the chained one", m.Len())
+	}
+	m.Update(time.Second)
+	if m.Len() != 0 {
+		t.Fatalf("got %d tweens; want 0", m.Len())
+	}
+	if len(values) != 2 || values[0] != 1 || values[1] != 2 {
+		t.Fatalf("got values %v; want [1 2]", values)
+	}
+}